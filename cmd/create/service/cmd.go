@@ -17,8 +17,10 @@ limitations under the License.
 package service
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
@@ -26,6 +28,8 @@ import (
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/roleset"
+	"github.com/openshift/rosa/pkg/concurrency"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
@@ -34,6 +38,9 @@ import (
 )
 
 var args ocm.CreateManagedServiceArgs
+var sourceARN string
+var roleSetVersion string
+var fromFile string
 
 var Cmd = &cobra.Command{
 	Use:   "service",
@@ -63,6 +70,48 @@ func init() {
 		"",
 		"Name of the cluster.",
 	)
+
+	flags.StringVar(
+		&args.AuditLogRoleARN,
+		"audit-log-arn",
+		"",
+		"The ARN of the IAM role that the control plane will assume to forward "+
+			"Kubernetes API-server audit logs to AWS CloudWatch Logs. "+
+			"Leave unset to keep audit log forwarding disabled.",
+	)
+
+	flags.StringVar(
+		&sourceARN,
+		"source-arn",
+		"",
+		"ARN expected to appear in the 'aws:SourceArn' condition of each account "+
+			"role's trust policy, to protect against the confused deputy problem. "+
+			"When set, rosa refuses to use a role whose trust policy doesn't "+
+			"condition on it; AWS populates 'aws:SourceArn' itself once the ROSA "+
+			"service assumes the role, rosa does not set it. Leave unset to skip "+
+			"this validation.",
+	)
+
+	flags.StringVar(
+		&roleSetVersion,
+		"role-set-version",
+		"",
+		"OpenShift minor version of the account and operator role set to use, e.g. 4.11. "+
+			"Run 'rosa list role-sets' to see the available versions. Defaults to the "+
+			"version used to create the service.",
+	)
+
+	flags.StringVar(
+		&fromFile,
+		"from-file",
+		"",
+		"Path to a YAML or JSON file declaring the service to create "+
+			"(serviceName, clusterName, region, version, awsRoleARNs, operatorRolesPrefix, "+
+			"auditLogRoleARN, etcdEncryptionKmsARN and parameters). "+
+			"When set, the account role ARNs are taken from the spec verbatim "+
+			"instead of being auto-discovered, and operator role ARNs are built "+
+			"from the spec's operatorRolesPrefix when it is set.",
+	)
 }
 
 func run(cmd *cobra.Command, _ []string) {
@@ -86,11 +135,54 @@ func run(cmd *cobra.Command, _ []string) {
 
 	awsClient := aws.GetAWSClientForUserRegion(reporter, logger)
 
-	// Openshift version to use.
-	// Hard-coding 4.9 for now
+	var spec *ocm.ManagedServiceSpec
+	if fromFile != "" {
+		spec, err = ocm.ReadManagedServiceSpec(fromFile)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			os.Exit(1)
+		}
+		args.ServiceName = spec.ServiceName
+		args.ClusterName = spec.ClusterName
+		args.AuditLogRoleARN = spec.AuditLogRoleARN
+		args.EtcdEncryptionKmsARN = spec.EtcdEncryptionKmsARN
+	}
+
+	if args.AuditLogRoleARN != "" {
+		_, err := arn.Parse(args.AuditLogRoleARN)
+		if err != nil {
+			reporter.Errorf("Expected a valid value for '--audit-log-arn': %s", err)
+			os.Exit(1)
+		}
+		err = awsClient.ValidateAuditLogRoleARN(args.AuditLogRoleARN)
+		if err != nil {
+			reporter.Errorf("Failed to validate audit log role '%s': %s", args.AuditLogRoleARN, err)
+			os.Exit(1)
+		}
+	}
+
+	// Openshift version to use. Defaults to 4.9 when not pinned by a
+	// '--from-file' spec, so CI and other automated callers can override it
+	// without a code change.
 	version := "4.9"
+	if spec != nil && spec.Version != "" {
+		version = spec.Version
+	}
 	minor := ocm.GetVersionMinor(version)
-	role := aws.AccountRoles[aws.InstallerAccountRole]
+
+	// Role set version to use for both account and operator roles. Defaults
+	// to the cluster's own version, but --role-set-version can pin both kinds
+	// of role to an older or newer role set than the cluster itself.
+	rolesVersion := minor
+	if roleSetVersion != "" {
+		rolesVersion = roleSetVersion
+	}
+
+	role, err := aws.AccountRole(aws.InstallerAccountRole, rolesVersion)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
 
 	// Find all installer roles in the current account using AWS resource tags
 	var roleARN string
@@ -99,77 +191,119 @@ func run(cmd *cobra.Command, _ []string) {
 	var workerRoleARN string
 	var hasRoles bool
 
-	roleARNs, err := awsClient.FindRoleARNs(aws.InstallerAccountRole, minor)
-	if err != nil {
-		reporter.Errorf("Failed to find %s role: %s", role.Name, err)
-		os.Exit(1)
-	}
-
-	if len(roleARNs) > 1 {
-		defaultRoleARN := roleARNs[0]
-		// Prioritize roles with the default prefix
-		for _, rARN := range roleARNs {
-			if strings.Contains(rARN, fmt.Sprintf("%s-%s-Role", aws.DefaultPrefix, role.Name)) {
-				defaultRoleARN = rARN
-			}
-		}
-		reporter.Warnf("More than one %s role found, going with %s", role.Name, defaultRoleARN)
-		roleARN = defaultRoleARN
-	} else if len(roleARNs) == 1 {
-		if !output.HasFlag() || reporter.IsTerminal() {
-			reporter.Infof("Using %s for the %s role", roleARNs[0], role.Name)
+	if spec != nil {
+		roleARN = spec.AwsRoleARNs.Installer
+		supportRoleARN = spec.AwsRoleARNs.Support
+		controlPlaneRoleARN = spec.AwsRoleARNs.ControlPlane
+		workerRoleARN = spec.AwsRoleARNs.Worker
+		err = validateSpecRoleARNs(awsClient, rolesVersion, sourceARN, spec.AwsRoleARNs)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			os.Exit(1)
 		}
-		roleARN = roleARNs[0]
+		hasRoles = true
 	} else {
-		reporter.Errorf("No account roles found. " +
-			"You will need to run 'rosa create account-roles' to create them first.")
-	}
-
-	if roleARN != "" {
-		// Get role prefix
-		rolePrefix, err := getAccountRolePrefix(roleARN, role)
+		roleARNs, err := awsClient.FindRoleARNs(aws.InstallerAccountRole, rolesVersion)
 		if err != nil {
-			reporter.Errorf("Failed to find prefix from %s account role", role.Name)
+			reporter.Errorf("Failed to find %s role: %s", role.Name, err)
 			os.Exit(1)
 		}
-		reporter.Debugf("Using '%s' as the role prefix", rolePrefix)
 
-		hasRoles = true
-		for roleType, role := range aws.AccountRoles {
-			if roleType == aws.InstallerAccountRole {
-				// Already dealt with
-				continue
+		if len(roleARNs) > 1 {
+			defaultRoleARN := roleARNs[0]
+			// Prioritize roles with the default prefix
+			for _, rARN := range roleARNs {
+				if strings.Contains(rARN, fmt.Sprintf("%s-%s-Role", aws.DefaultPrefix, role.Name)) {
+					defaultRoleARN = rARN
+				}
+			}
+			reporter.Warnf("More than one %s role found, going with %s", role.Name, defaultRoleARN)
+			roleARN = defaultRoleARN
+		} else if len(roleARNs) == 1 {
+			if !output.HasFlag() || reporter.IsTerminal() {
+				reporter.Infof("Using %s for the %s role", roleARNs[0], role.Name)
 			}
-			roleARNs, err := awsClient.FindRoleARNs(roleType, minor)
+			roleARN = roleARNs[0]
+		} else {
+			reporter.Errorf("No account roles found. " +
+				"You will need to run 'rosa create account-roles' to create them first.")
+		}
+
+		if roleARN != "" {
+			// Get role prefix
+			rolePrefix, err := getAccountRolePrefix(roleARN, role)
 			if err != nil {
-				reporter.Errorf("Failed to find %s role: %s", role.Name, err)
+				reporter.Errorf("Failed to find prefix from %s account role", role.Name)
 				os.Exit(1)
 			}
-			selectedARN := ""
-			for _, rARN := range roleARNs {
-				if strings.Contains(rARN, fmt.Sprintf("%s-%s-Role", rolePrefix, role.Name)) {
-					selectedARN = rARN
+			reporter.Debugf("Using '%s' as the role prefix", rolePrefix)
+
+			hasRoles = true
+
+			// Look up the remaining account roles in parallel instead of
+			// serially paginating iam:ListRoles once per role type.
+			var remainingRoleTypes []aws.AccountRoleType
+			for _, roleType := range aws.AccountRoleTypes {
+				if roleType != aws.InstallerAccountRole {
+					remainingRoleTypes = append(remainingRoleTypes, roleType)
 				}
 			}
-			if selectedARN == "" {
-				reporter.Errorf("No %s account roles found. "+
-					"You will need to run 'rosa create account-roles' to create them first.",
-					role.Name)
-				interactive.Enable()
-				hasRoles = false
+			selectedARNs := make([]string, len(remainingRoleTypes))
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			jobs := make([]func(context.Context) error, len(remainingRoleTypes))
+			for i, roleType := range remainingRoleTypes {
+				i, roleType := i, roleType
+				jobs[i] = func(_ context.Context) error {
+					role, err := aws.AccountRole(roleType, rolesVersion)
+					if err != nil {
+						return err
+					}
+					roleARNs, err := awsClient.FindRoleARNs(roleType, rolesVersion)
+					if err != nil {
+						return fmt.Errorf("failed to find %s role: %s", role.Name, err)
+					}
+					for _, rARN := range roleARNs {
+						if strings.Contains(rARN, fmt.Sprintf("%s-%s-Role", rolePrefix, role.Name)) {
+							selectedARNs[i] = rARN
+						}
+					}
+					return nil
+				}
 			}
-			if !output.HasFlag() || reporter.IsTerminal() {
-				reporter.Infof("Using %s for the %s role", selectedARN, role.Name)
+			err = concurrency.Run(ctx, len(jobs), jobs)
+			if err != nil {
+				reporter.Errorf("%v", err)
+				os.Exit(1)
 			}
-			switch roleType {
-			case aws.InstallerAccountRole:
-				roleARN = selectedARN
-			case aws.SupportAccountRole:
-				supportRoleARN = selectedARN
-			case aws.ControlPlaneAccountRole:
-				controlPlaneRoleARN = selectedARN
-			case aws.WorkerAccountRole:
-				workerRoleARN = selectedARN
+
+			for i, roleType := range remainingRoleTypes {
+				role, err := aws.AccountRole(roleType, rolesVersion)
+				if err != nil {
+					reporter.Errorf("%v", err)
+					os.Exit(1)
+				}
+				selectedARN := selectedARNs[i]
+				if selectedARN == "" {
+					reporter.Errorf("No %s account roles found. "+
+						"You will need to run 'rosa create account-roles' to create them first.",
+						role.Name)
+					interactive.Enable()
+					hasRoles = false
+				}
+				if !output.HasFlag() || reporter.IsTerminal() {
+					reporter.Infof("Using %s for the %s role", selectedARN, role.Name)
+				}
+				switch roleType {
+				case aws.SupportAccountRole:
+					supportRoleARN = selectedARN
+				case aws.ControlPlaneAccountRole:
+					controlPlaneRoleARN = selectedARN
+				case aws.WorkerAccountRole:
+					workerRoleARN = selectedARN
+				}
 			}
 		}
 	}
@@ -178,6 +312,26 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	if sourceARN != "" {
+		discoveredRoleARNs := map[aws.AccountRoleType]string{
+			aws.InstallerAccountRole:    roleARN,
+			aws.SupportAccountRole:      supportRoleARN,
+			aws.ControlPlaneAccountRole: controlPlaneRoleARN,
+			aws.WorkerAccountRole:       workerRoleARN,
+		}
+		for roleType, discoveredARN := range discoveredRoleARNs {
+			role, err := aws.AccountRole(roleType, rolesVersion)
+			if err != nil {
+				reporter.Errorf("%v", err)
+				os.Exit(1)
+			}
+			if err := awsClient.ValidateTrustPolicyHasSourceArnCondition(discoveredARN, sourceARN); err != nil {
+				reporter.Errorf("Failed to validate %s account role '%s': %v", role.Name, discoveredARN, err)
+				os.Exit(1)
+			}
+		}
+	}
+
 	args.AwsRoleARN = roleARN
 	args.AwsSupportRoleARN = supportRoleARN
 	args.AwsControlPlaneRoleARN = controlPlaneRoleARN
@@ -191,9 +345,18 @@ func run(cmd *cobra.Command, _ []string) {
 	}
 
 	operatorRolesPrefix := getRolePrefix(args.ClusterName)
+	if spec != nil && spec.OperatorRolesPrefix != "" {
+		operatorRolesPrefix = spec.OperatorRolesPrefix
+	}
 	operatorIAMRoleList := []ocm.OperatorIAMRole{}
 
-	for _, operator := range aws.CredentialRequests {
+	roles, err := roleset.ForVersion(rolesVersion)
+	if err != nil {
+		reporter.Errorf("Failed to load role set: %v", err)
+		os.Exit(1)
+	}
+
+	for _, operator := range roles.Operators() {
 		//If the cluster version is less than the supported operator version
 		if operator.MinVersion != "" {
 			isSupported, err := ocm.CheckSupportedVersion(ocm.GetVersionMinor(version), operator.MinVersion)
@@ -205,6 +368,17 @@ func run(cmd *cobra.Command, _ []string) {
 				continue
 			}
 		}
+		// If the cluster version is past the operator's end-of-life version, skip it too.
+		if operator.MaxVersion != "" {
+			isSupported, err := ocm.CheckSupportedVersion(operator.MaxVersion, ocm.GetVersionMinor(version))
+			if err != nil {
+				reporter.Errorf("Error validating operator role '%s' version %s", operator.Name, err)
+				os.Exit(1)
+			}
+			if !isSupported {
+				continue
+			}
+		}
 		operatorIAMRoleList = append(operatorIAMRoleList, ocm.OperatorIAMRole{
 			Name:      operator.Name,
 			Namespace: operator.Namespace,
@@ -245,7 +419,11 @@ func run(cmd *cobra.Command, _ []string) {
 	args.AwsAccountID = awsCreator.AccountID
 
 	// Get AWS region
-	args.AwsRegion, err = aws.GetRegion("")
+	regionOverride := ""
+	if spec != nil {
+		regionOverride = spec.Region
+	}
+	args.AwsRegion, err = aws.GetRegion(regionOverride)
 	if err != nil {
 		reporter.Errorf("Error getting region: %v", err)
 		os.Exit(1)
@@ -253,19 +431,23 @@ func run(cmd *cobra.Command, _ []string) {
 	reporter.Infof("Using AWS region: %s", args.AwsRegion)
 
 	// Parameter logic
-	addOn, err := ocmClient.GetAddOn(args.ServiceName)
-	if err != nil {
-		reporter.Errorf("Failed to process service parameters: %s", err)
-	}
-	addOnParameters := addOn.Parameters()
-	if addOnParameters != nil {
-		addOnParameters.Each(func(param *cmv1.AddOnParameter) bool {
-			flag := cmd.Flags().Lookup(param.ID())
-			if flag != nil {
-				args.Parameters[param.ID()] = flag.Value.String()
-			}
-			return true
-		})
+	if spec != nil && spec.Parameters != nil {
+		args.Parameters = spec.Parameters
+	} else {
+		addOn, err := ocmClient.GetAddOn(args.ServiceName)
+		if err != nil {
+			reporter.Errorf("Failed to process service parameters: %s", err)
+		}
+		addOnParameters := addOn.Parameters()
+		if addOnParameters != nil {
+			addOnParameters.Each(func(param *cmv1.AddOnParameter) bool {
+				flag := cmd.Flags().Lookup(param.ID())
+				if flag != nil {
+					args.Parameters[param.ID()] = flag.Value.String()
+				}
+				return true
+			})
+		}
 	}
 
 	// Creating the service
@@ -285,9 +467,52 @@ func run(cmd *cobra.Command, _ []string) {
 		"\t%s\n"+
 		"\t%s\n",
 		rolesCMD, oidcCMD)
+
+	if args.AuditLogRoleARN != "" {
+		logGroupCMD := fmt.Sprintf("aws logs create-log-group --log-group-name /rosa/%s/audit", args.ClusterName)
+		reporter.Infof("Audit log forwarding is enabled using role '%s'.\n"+
+			"If the destination log group does not exist yet, create it with:\n\n"+
+			"\t%s\n",
+			args.AuditLogRoleARN, logGroupCMD)
+	}
+}
+
+// validateSpecRoleARNs checks that every account role ARN declared in a
+// '--from-file' spec is a well-formed ARN of an existing role with the
+// managed policy required for its role type attached. When sourceARN is
+// set, it also checks that each role's trust policy conditions on
+// 'aws:SourceArn' matching it.
+func validateSpecRoleARNs(awsClient aws.Client, minor, sourceARN string, roleARNs ocm.ServiceRoleARNs) error {
+	specRoles := map[aws.AccountRoleType]string{
+		aws.InstallerAccountRole:    roleARNs.Installer,
+		aws.SupportAccountRole:      roleARNs.Support,
+		aws.ControlPlaneAccountRole: roleARNs.ControlPlane,
+		aws.WorkerAccountRole:       roleARNs.Worker,
+	}
+	for roleType, roleARN := range specRoles {
+		role, err := aws.AccountRole(roleType, minor)
+		if err != nil {
+			return err
+		}
+		if roleARN == "" {
+			return fmt.Errorf("service spec is missing the %s account role ARN", role.Name)
+		}
+		if _, err := arn.Parse(roleARN); err != nil {
+			return fmt.Errorf("'%s' is not a valid ARN for the %s account role: %v", roleARN, role.Name, err)
+		}
+		if err := awsClient.ValidateAccountRoleARN(roleARN, roleType, minor); err != nil {
+			return fmt.Errorf("failed to validate %s account role '%s': %v", role.Name, roleARN, err)
+		}
+		if sourceARN != "" {
+			if err := awsClient.ValidateTrustPolicyHasSourceArnCondition(roleARN, sourceARN); err != nil {
+				return fmt.Errorf("failed to validate %s account role '%s': %v", role.Name, roleARN, err)
+			}
+		}
+	}
+	return nil
 }
 
-func getAccountRolePrefix(roleARN string, role aws.AccountRole) (string, error) {
+func getAccountRolePrefix(roleARN string, role roleset.AccountRole) (string, error) {
 	parsedARN, err := arn.Parse(roleARN)
 	if err != nil {
 		return "", err
@@ -301,7 +526,7 @@ func getRolePrefix(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, ocm.RandomLabel(4))
 }
 
-func getOperatorRoleArn(prefix string, operator aws.Operator, creator *aws.Creator) string {
+func getOperatorRoleArn(prefix string, operator roleset.OperatorRole, creator *aws.Creator) string {
 	role := fmt.Sprintf("%s-%s-%s", prefix, operator.Namespace, operator.Name)
 	if len(role) > 64 {
 		role = role[0:64]
@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	serviceID string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "service",
+	Short: "Show details of a managed service",
+	Long:  "Show details of a managed service.",
+	Example: `  # Describe a managed service
+  rosa describe service --id=2330dkNMgh1uOjsMwTPVUR4JtkB
+
+  # Print a service as a '--from-file' spec
+  rosa describe service --id=2330dkNMgh1uOjsMwTPVUR4JtkB --output=spec`,
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(
+		&args.serviceID,
+		"id",
+		"",
+		"ID of the managed service to describe.",
+	)
+
+	output.AddFlag(Cmd)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	if args.serviceID == "" {
+		reporter.Errorf("You must specify a service ID with the '--id' flag")
+		os.Exit(1)
+	}
+
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	service, err := ocmClient.GetManagedService(args.serviceID)
+	if err != nil {
+		reporter.Errorf("Failed to get service '%s': %v", args.serviceID, err)
+		os.Exit(1)
+	}
+
+	if !output.HasFlag() || cmd.Flags().Lookup("output").Value.String() != "spec" {
+		reporter.Infof("%v", service)
+		return
+	}
+
+	spec := ocm.ManagedServiceSpecFromService(service)
+	yaml, err := ocm.MarshalManagedServiceSpec(spec)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	fmt.Print(yaml)
+}
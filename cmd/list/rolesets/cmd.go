@@ -0,0 +1,56 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolesets
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws/roleset"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "role-sets",
+	Aliases: []string{"role-set"},
+	Short:   "List account and operator role sets",
+	Long:    "List the account and operator IAM role sets rosa knows how to create, by OpenShift version.",
+	Example: `  # List the supported role sets
+  rosa list role-sets`,
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+
+	roleSets, err := roleset.All()
+	if err != nil {
+		reporter.Errorf("Failed to load role sets: %v", err)
+		os.Exit(1)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "VERSION\tACCOUNT ROLES\tOPERATOR ROLES\n")
+	for _, rs := range roleSets {
+		fmt.Fprintf(writer, "%s\t%d\t%d\n", rs.Version, len(rs.AccountRoles), len(rs.OperatorRoles))
+	}
+	writer.Flush()
+}
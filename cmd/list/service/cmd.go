@@ -70,6 +70,11 @@ func run(cmd *cobra.Command, argv []string) {
 		}
 	}()
 
+	// TODO: ListManagedServices (pkg/ocm) fetches the whole list in a single
+	// call. Giving it a paged signature - size/page parameters and a
+	// per-row callback it can stop early via a context - belongs with the
+	// rest of the OCM client, not this command, since every caller of that
+	// client would need to agree on the new contract.
 	servicesList, err := ocmClient.ListManagedServices(1000)
 	if err != nil {
 		reporter.Errorf("Failed to retrieve list of managed services: %v", err)
@@ -79,7 +84,7 @@ func run(cmd *cobra.Command, argv []string) {
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	fmt.Fprintf(writer, "ID\tSERVICE\tSTATE\n")
 	servicesList.Each(func(srv *msv1.ManagedService) bool {
-		fmt.Fprintf(writer,"%s\t%s\t%s\n",srv.ID(),srv.Service(),srv.ServiceState())
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", srv.ID(), srv.Service(), srv.ServiceState())
 		return true
 	})
 	writer.Flush()
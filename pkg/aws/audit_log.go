@@ -0,0 +1,207 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// installerServicePrincipal is the principal the audit log role's trust
+// policy must allow to assume it, so that only the ROSA control plane (and
+// not an arbitrary AWS principal) can forward audit logs on the customer's
+// behalf.
+const installerServicePrincipal = "hypershift.amazonaws.com"
+
+// auditLogRequiredActions are the CloudWatch Logs permissions the audit log
+// role must grant so the control plane can ship kube-apiserver audit logs.
+var auditLogRequiredActions = []string{
+	"logs:CreateLogStream",
+	"logs:PutLogEvents",
+	"logs:DescribeLogGroups",
+}
+
+type policyDocument struct {
+	Statement []policyStatement `json:"Statement"`
+}
+
+type policyStatement struct {
+	Effect    string      `json:"Effect"`
+	Action    interface{} `json:"Action"`
+	Principal interface{} `json:"Principal"`
+	Condition interface{} `json:"Condition"`
+}
+
+// ValidateAuditLogRoleARN checks that roleARN names an existing IAM role
+// whose trust policy allows the installer/control-plane service principal
+// to assume it, and whose attached policies grant the CloudWatch Logs
+// permissions required to forward audit logs.
+func (c *awsClient) ValidateAuditLogRoleARN(roleARN string) error {
+	roleName, err := roleNameFromARN(roleARN)
+	if err != nil {
+		return err
+	}
+
+	getRoleOutput, err := c.iamClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		return fmt.Errorf("role '%s' not found: %v", roleName, err)
+	}
+
+	trustPolicy, err := decodeTrustPolicy(*getRoleOutput.Role.AssumeRolePolicyDocument)
+	if err != nil {
+		return fmt.Errorf("failed to parse trust policy for role '%s': %v", roleName, err)
+	}
+	if !trustPolicyAllowsPrincipal(trustPolicy, installerServicePrincipal) {
+		return fmt.Errorf("role '%s' does not trust '%s' to assume it", roleName, installerServicePrincipal)
+	}
+
+	actions, err := c.attachedPolicyActions(roleName)
+	if err != nil {
+		return err
+	}
+	for _, required := range auditLogRequiredActions {
+		if !actions[required] {
+			return fmt.Errorf("role '%s' is missing the '%s' permission required to forward audit logs",
+				roleName, required)
+		}
+	}
+	return nil
+}
+
+func decodeTrustPolicy(encoded string) (*policyDocument, error) {
+	decoded, err := url.QueryUnescape(encoded)
+	if err != nil {
+		return nil, err
+	}
+	doc := &policyDocument{}
+	if err := json.Unmarshal([]byte(decoded), doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func trustPolicyAllowsPrincipal(doc *policyDocument, principal string) bool {
+	for _, statement := range doc.Statement {
+		if !strings.EqualFold(statement.Effect, "Allow") {
+			continue
+		}
+		if principalContains(statement.Principal, principal) {
+			return true
+		}
+	}
+	return false
+}
+
+// principalContains checks an IAM policy Principal element (which
+// encoding/json decodes as a bare string, a list of strings, or a map of
+// principal-type to string/list) for the given principal value.
+func principalContains(principal interface{}, want string) bool {
+	switch p := principal.(type) {
+	case string:
+		return p == want
+	case []interface{}:
+		for _, entry := range p {
+			if s, ok := entry.(string); ok && s == want {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, value := range p {
+			if principalContains(value, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// attachedPolicyActions returns the set of actions granted by every managed
+// and inline policy attached to the given role.
+func (c *awsClient) attachedPolicyActions(roleName string) (map[string]bool, error) {
+	actions := map[string]bool{}
+
+	attached, err := c.iamClient.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attached policies for role '%s': %v", roleName, err)
+	}
+	for _, policy := range attached.AttachedPolicies {
+		version, err := c.iamClient.GetPolicy(&iam.GetPolicyInput{PolicyArn: policy.PolicyArn})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get policy '%s': %v", *policy.PolicyArn, err)
+		}
+		policyVersion, err := c.iamClient.GetPolicyVersion(&iam.GetPolicyVersionInput{
+			PolicyArn: policy.PolicyArn,
+			VersionId: version.Policy.DefaultVersionId,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get policy document for '%s': %v", *policy.PolicyArn, err)
+		}
+		addPolicyActions(actions, *policyVersion.PolicyVersion.Document)
+	}
+
+	inline, err := c.iamClient.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: aws.String(roleName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inline policies for role '%s': %v", roleName, err)
+	}
+	for _, policyName := range inline.PolicyNames {
+		policy, err := c.iamClient.GetRolePolicy(&iam.GetRolePolicyInput{
+			RoleName:   aws.String(roleName),
+			PolicyName: policyName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get inline policy '%s': %v", *policyName, err)
+		}
+		addPolicyActions(actions, *policy.PolicyDocument)
+	}
+
+	return actions, nil
+}
+
+func addPolicyActions(actions map[string]bool, encodedDocument string) {
+	decoded, err := url.QueryUnescape(encodedDocument)
+	if err != nil {
+		return
+	}
+	doc := &policyDocument{}
+	if err := json.Unmarshal([]byte(decoded), doc); err != nil {
+		return
+	}
+	for _, statement := range doc.Statement {
+		if !strings.EqualFold(statement.Effect, "Allow") {
+			continue
+		}
+		switch action := statement.Action.(type) {
+		case string:
+			actions[action] = true
+		case []interface{}:
+			for _, entry := range action {
+				if s, ok := entry.(string); ok {
+					actions[s] = true
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package aws wraps the AWS SDK calls rosa makes against IAM and STS when
+// creating and inspecting account and operator roles.
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+
+	"github.com/openshift/rosa/pkg/logging"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// AccountRoleType identifies one of the account-wide IAM roles used by ROSA
+// clusters.
+type AccountRoleType string
+
+const (
+	InstallerAccountRole    AccountRoleType = "Installer"
+	SupportAccountRole      AccountRoleType = "Support"
+	ControlPlaneAccountRole AccountRoleType = "ControlPlane"
+	WorkerAccountRole       AccountRoleType = "Worker"
+)
+
+// AccountRoleTypes is every account role type rosa creates and looks up,
+// in the order they're normally presented to users.
+var AccountRoleTypes = []AccountRoleType{
+	InstallerAccountRole,
+	SupportAccountRole,
+	ControlPlaneAccountRole,
+	WorkerAccountRole,
+}
+
+// DefaultPrefix is used for account role names when the user doesn't supply
+// an explicit --prefix to 'rosa create account-roles'.
+const DefaultPrefix = "ManagedOpenShift"
+
+// Creator identifies the AWS IAM entity used to run rosa commands.
+type Creator struct {
+	AccountID string
+	ARN       string
+}
+
+// Client is the set of AWS operations rosa commands need.
+type Client interface {
+	FindRoleARNs(roleType AccountRoleType, minor string) ([]string, error)
+	ValidateRoleNameAvailable(name string) error
+	ValidateAuditLogRoleARN(roleARN string) error
+	ValidateAccountRoleARN(roleARN string, roleType AccountRoleType, minor string) error
+	ValidateTrustPolicyHasSourceArnCondition(roleARN, sourceARN string) error
+	GetCreator() (*Creator, error)
+}
+
+type awsClient struct {
+	reporter  rprtr.Reporter
+	logger    *logging.Logger
+	iamClient *iam.IAM
+	stsClient *sts.STS
+}
+
+// GetAWSClientForUserRegion builds a Client using the region and credentials
+// configured for the current user (env vars, shared config or profile).
+func GetAWSClientForUserRegion(reporter rprtr.Reporter, logger *logging.Logger) Client {
+	sess := session.Must(session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	}))
+
+	return &awsClient{
+		reporter:  reporter,
+		logger:    logger,
+		iamClient: iam.New(sess),
+		stsClient: sts.New(sess),
+	}
+}
+
+// GetRegion returns the given region, or the region configured for the
+// current user when region is empty.
+func GetRegion(region string) (string, error) {
+	if region != "" {
+		return region, nil
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create AWS session: %v", err)
+	}
+	if sess.Config.Region == nil || *sess.Config.Region == "" {
+		return "", fmt.Errorf("no AWS region set, use --region or configure a default region")
+	}
+	return *sess.Config.Region, nil
+}
+
+func (c *awsClient) GetCreator() (*Creator, error) {
+	identity, err := c.stsClient.GetCallerIdentity(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %v", err)
+	}
+	return &Creator{
+		AccountID: *identity.Account,
+		ARN:       *identity.Arn,
+	}, nil
+}
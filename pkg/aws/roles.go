@@ -0,0 +1,243 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"github.com/openshift/rosa/pkg/aws/roleset"
+)
+
+// roleNameFromARN extracts the role name from an IAM role ARN
+// (arn:aws:iam::<account>:role/<name>).
+func roleNameFromARN(roleARN string) (string, error) {
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return "", fmt.Errorf("'%s' is not a valid ARN: %v", roleARN, err)
+	}
+	parts := strings.SplitN(parsed.Resource, "/", 2)
+	if len(parts) != 2 || parts[0] != "role" {
+		return "", fmt.Errorf("'%s' does not reference an IAM role", roleARN)
+	}
+	return parts[1], nil
+}
+
+// roleTypeTagKey is the IAM resource tag rosa stamps on every account role
+// it creates, so that FindRoleARNs can recover them by role type.
+const roleTypeTagKey = "rosa_role_type"
+
+// roleVersionTagKey is the IAM resource tag rosa stamps with the OpenShift
+// minor version an account role was created for, so that two roles of the
+// same type from different role sets aren't treated as interchangeable.
+// TODO: 'rosa create account-roles' needs to be updated to stamp this tag
+// on every role it creates. Until it does, FindRoleARNs/ValidateAccountRoleARN
+// fall back to matching on roleTypeTagKey alone so roles created before that
+// change don't stop being discoverable.
+const roleVersionTagKey = "rosa_role_version"
+
+// FindRoleARNs returns the ARNs of the account roles of the given type that
+// are tagged for the given OpenShift minor version. Falls back to matching
+// on role type alone, with a warning, for roles that predate
+// roleVersionTagKey.
+func (c *awsClient) FindRoleARNs(roleType AccountRoleType, minor string) ([]string, error) {
+	roles, err := c.listRolesByTags(map[string]string{
+		roleTypeTagKey:    string(roleType),
+		roleVersionTagKey: minor,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		roles, err = c.listRolesByTags(map[string]string{roleTypeTagKey: string(roleType)})
+		if err != nil {
+			return nil, err
+		}
+		// Roles tagged for another OpenShift version are not part of the fallback.
+		var untagged []*iam.Role
+		for _, role := range roles {
+			versioned := false
+			for _, tag := range role.Tags {
+				if tag.Key != nil && *tag.Key == roleVersionTagKey {
+					versioned = true
+				}
+			}
+			if !versioned {
+				untagged = append(untagged, role)
+			}
+		}
+		roles = untagged
+		if len(roles) > 0 {
+			c.reporter.Warnf("No %s account roles tagged for OpenShift %s found; falling back to "+
+				"matching roles without a version tag by role type alone. Re-run 'rosa create account-roles' once it tags roles "+
+				"with their OpenShift version to remove this warning.", roleType, minor)
+		}
+	}
+	arns := make([]string, 0, len(roles))
+	for _, role := range roles {
+		arns = append(arns, *role.Arn)
+	}
+	return arns, nil
+}
+
+// listRolesByTags pages through iam:ListRoles and returns the roles
+// carrying every given tag key/value pair.
+// TODO: each call pages through the full account's roles independently;
+// 'rosa create service' looks up the installer role first and then the
+// other three account role types concurrently via concurrency.Run, so
+// discovery is at least four full iam:ListRoles paginations rather than the
+// single cached pagination the four lookups could share. Cache the page results the first call fetches and filter
+// the remaining three lookups from that cache instead of re-paginating.
+func (c *awsClient) listRolesByTags(tags map[string]string) ([]*iam.Role, error) {
+	var matched []*iam.Role
+	var marker *string
+	for {
+		output, err := c.iamClient.ListRoles(&iam.ListRolesInput{
+			Marker: marker,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list IAM roles: %v", err)
+		}
+		for _, role := range output.Roles {
+			if roleHasAllTags(role.Tags, tags) {
+				matched = append(matched, role)
+			}
+		}
+		if output.IsTruncated == nil || !*output.IsTruncated {
+			break
+		}
+		marker = output.Marker
+	}
+	return matched, nil
+}
+
+// roleHasAllTags reports whether roleTags carries every key/value pair in
+// want.
+func roleHasAllTags(roleTags []*iam.Tag, want map[string]string) bool {
+	for key, value := range want {
+		if !roleHasTag(roleTags, key, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// AccountRole returns the definition of the given account role type from
+// the role set registered for minor. pkg/aws/roleset is the single source
+// of truth for account-role names and managed-policy ARNs, so that rolling
+// out a new managed policy version is a manifest change, not a code change.
+func AccountRole(roleType AccountRoleType, minor string) (roleset.AccountRole, error) {
+	roles, err := roleset.ForVersion(minor)
+	if err != nil {
+		return roleset.AccountRole{}, err
+	}
+	role, ok := roles.AccountRole(string(roleType))
+	if !ok {
+		return roleset.AccountRole{}, fmt.Errorf("no %s account role defined for OpenShift %s", roleType, minor)
+	}
+	return role, nil
+}
+
+// ValidateAccountRoleARN checks that roleARN names an existing IAM role that
+// is tagged for the given account role type and OpenShift minor version, and
+// has the managed policy required for that role type attached. A role with
+// no version tag at all is accepted with a warning. Used to
+// validate the account role ARNs pinned by a 'rosa create service
+// --from-file' spec, since those are taken verbatim instead of being
+// auto-discovered via FindRoleARNs.
+func (c *awsClient) ValidateAccountRoleARN(roleARN string, roleType AccountRoleType, minor string) error {
+	roleName, err := roleNameFromARN(roleARN)
+	if err != nil {
+		return err
+	}
+
+	role, err := AccountRole(roleType, minor)
+	if err != nil {
+		return err
+	}
+
+	getRoleOutput, err := c.iamClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		return fmt.Errorf("role '%s' not found: %v", roleName, err)
+	}
+
+	if !roleHasTag(getRoleOutput.Role.Tags, roleTypeTagKey, string(roleType)) {
+		return fmt.Errorf("role '%s' is not tagged as a %s account role", roleName, role.Name)
+	}
+	if !roleHasTag(getRoleOutput.Role.Tags, roleVersionTagKey, minor) {
+		for _, tag := range getRoleOutput.Role.Tags {
+			if tag.Key != nil && *tag.Key == roleVersionTagKey {
+				return fmt.Errorf("role '%s' is tagged for OpenShift %s, not %s",
+					roleName, aws.StringValue(tag.Value), minor)
+			}
+		}
+		c.reporter.Warnf("Role '%s' is not tagged for OpenShift %s; skipping the version check until "+
+			"'rosa create account-roles' stamps it. Re-run that command once it does to remove this "+
+			"warning.", roleName, minor)
+	}
+
+	attached, err := c.iamClient.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list attached policies for role '%s': %v", roleName, err)
+	}
+	hasManagedPolicy := false
+	for _, policy := range attached.AttachedPolicies {
+		if policy.PolicyArn != nil && *policy.PolicyArn == role.ManagedPolicyARN {
+			hasManagedPolicy = true
+			break
+		}
+	}
+	if !hasManagedPolicy {
+		return fmt.Errorf("role '%s' does not have the '%s' managed policy attached",
+			roleName, role.ManagedPolicyARN)
+	}
+
+	return nil
+}
+
+func roleHasTag(tags []*iam.Tag, key, value string) bool {
+	for _, tag := range tags {
+		if tag.Key != nil && *tag.Key == key && tag.Value != nil && *tag.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRoleNameAvailable fails if an IAM role with the given name
+// already exists in the current account.
+func (c *awsClient) ValidateRoleNameAvailable(name string) error {
+	_, err := c.iamClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(name),
+	})
+	if err == nil {
+		return fmt.Errorf("role '%s' already exists", name)
+	}
+	if awsErr, ok := err.(interface{ Code() string }); ok && awsErr.Code() == iam.ErrCodeNoSuchEntityException {
+		return nil
+	}
+	return fmt.Errorf("failed to check for existing role '%s': %v", name, err)
+}
+
@@ -0,0 +1,141 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package roleset describes the versioned sets of account and operator IAM
+// roles that a ROSA cluster needs, so that adding a new operator or rolling
+// out a new managed policy is a manifest change rather than a code change.
+package roleset
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed manifests/*.json
+var embeddedManifests embed.FS
+
+// AccountRole describes one of the account-wide IAM roles (installer,
+// support, control-plane, worker) required to create a cluster.
+type AccountRole struct {
+	Name             string `json:"name"`
+	ManagedPolicyARN string `json:"managedPolicyARN"`
+}
+
+// OperatorRole describes a single operator credential request: the
+// namespace/service-account it authenticates as, the version range it
+// applies to and the managed policy its IAM role must carry.
+type OperatorRole struct {
+	Name             string `json:"name"`
+	Namespace        string `json:"namespace"`
+	MinVersion       string `json:"minVersion,omitempty"`
+	MaxVersion       string `json:"maxVersion,omitempty"`
+	ManagedPolicyARN string `json:"managedPolicyARN"`
+}
+
+// RoleSet is the full collection of account and operator roles required for
+// a given OpenShift minor version.
+type RoleSet struct {
+	Version       string         `json:"version"`
+	AccountRoles  []AccountRole  `json:"accountRoles"`
+	OperatorRoles []OperatorRole `json:"operatorRoles"`
+}
+
+// Operators returns the operator credential requests in this role set.
+func (r RoleSet) Operators() []OperatorRole {
+	return r.OperatorRoles
+}
+
+// AccountRole returns the account role definition with the given name (e.g.
+// "Installer") from this role set.
+func (r RoleSet) AccountRole(name string) (AccountRole, bool) {
+	for _, role := range r.AccountRoles {
+		if role.Name == name {
+			return role, true
+		}
+	}
+	return AccountRole{}, false
+}
+
+var cache map[string]RoleSet
+
+// ForVersion returns the RoleSet registered for the given OpenShift minor
+// version (e.g. "4.11").
+func ForVersion(minor string) (RoleSet, error) {
+	sets, err := load()
+	if err != nil {
+		return RoleSet{}, err
+	}
+	roleSet, ok := sets[minor]
+	if !ok {
+		return RoleSet{}, fmt.Errorf("no role set registered for OpenShift %s, "+
+			"run 'rosa list role-sets' to see the supported versions", minor)
+	}
+	return roleSet, nil
+}
+
+// All returns every versioned role set known to rosa, sorted by version.
+// Used by 'rosa list role-sets'.
+func All() ([]RoleSet, error) {
+	sets, err := load()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]RoleSet, 0, len(sets))
+	for _, roleSet := range sets {
+		result = append(result, roleSet)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a := strings.Split(result[i].Version, ".")
+		b := strings.Split(result[j].Version, ".")
+		for k := 0; k < len(a) && k < len(b); k++ {
+			x, _ := strconv.Atoi(a[k])
+			y, _ := strconv.Atoi(b[k])
+			if x != y {
+				return x < y
+			}
+		}
+		return len(a) < len(b)
+	})
+	return result, nil
+}
+
+func load() (map[string]RoleSet, error) {
+	if cache != nil {
+		return cache, nil
+	}
+	entries, err := embeddedManifests.ReadDir("manifests")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded role set manifests: %v", err)
+	}
+	sets := make(map[string]RoleSet, len(entries))
+	for _, entry := range entries {
+		data, err := embeddedManifests.ReadFile("manifests/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read role set manifest '%s': %v", entry.Name(), err)
+		}
+		var roleSet RoleSet
+		if err := json.Unmarshal(data, &roleSet); err != nil {
+			return nil, fmt.Errorf("failed to parse role set manifest '%s': %v", entry.Name(), err)
+		}
+		sets[roleSet.Version] = roleSet
+	}
+	cache = sets
+	return cache, nil
+}
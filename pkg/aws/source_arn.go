@@ -0,0 +1,100 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// ValidateTrustPolicyHasSourceArnCondition checks that an Allow statement in
+// roleARN's trust policy conditions on 'aws:SourceArn' matching sourceARN
+// (the statement's principal is not inspected), guarding against the confused-deputy
+// problem where a third party could otherwise reuse the role's trust
+// relationship to assume it on the customer's behalf.
+func (c *awsClient) ValidateTrustPolicyHasSourceArnCondition(roleARN, sourceARN string) error {
+	roleName, err := roleNameFromARN(roleARN)
+	if err != nil {
+		return err
+	}
+
+	getRoleOutput, err := c.iamClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		return fmt.Errorf("role '%s' not found: %v", roleName, err)
+	}
+
+	trustPolicy, err := decodeTrustPolicy(*getRoleOutput.Role.AssumeRolePolicyDocument)
+	if err != nil {
+		return fmt.Errorf("failed to parse trust policy for role '%s': %v", roleName, err)
+	}
+	if !trustPolicyHasSourceArnCondition(trustPolicy, sourceARN) {
+		return fmt.Errorf("role '%s' does not condition its trust policy on 'aws:SourceArn' matching '%s'",
+			roleName, sourceARN)
+	}
+	return nil
+}
+
+// trustPolicyHasSourceArnCondition reports whether any Allow statement
+// conditions on 'aws:SourceArn' (via StringEquals/StringLike/ArnEquals/
+// ArnLike) matching sourceARN.
+func trustPolicyHasSourceArnCondition(doc *policyDocument, sourceARN string) bool {
+	for _, statement := range doc.Statement {
+		if !strings.EqualFold(statement.Effect, "Allow") {
+			continue
+		}
+		if conditionContainsSourceArn(statement.Condition, sourceARN) {
+			return true
+		}
+	}
+	return false
+}
+
+func conditionContainsSourceArn(condition interface{}, sourceARN string) bool {
+	operators, ok := condition.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for operator, keys := range operators {
+		matching := false
+		for _, allowed := range []string{"StringEquals", "StringLike", "ArnEquals", "ArnLike"} {
+			if strings.EqualFold(operator, allowed) {
+				matching = true
+			}
+		}
+		if !matching {
+			continue
+		}
+		keyMap, ok := keys.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range keyMap {
+			if !strings.EqualFold(key, "aws:SourceArn") {
+				continue
+			}
+			if principalContains(value, sourceARN) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package concurrency provides small, reusable helpers for running a bounded
+// number of jobs in parallel and aborting them cleanly on cancellation.
+package concurrency
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Run executes each of jobs under an errgroup, allowing at most maxInFlight
+// of them to run at the same time. It returns the first error encountered,
+// cancelling the context derived from ctx that every job is given, so the
+// remaining in-flight jobs can abort early - AWS SDK
+// calls given a cancelled context return immediately instead of running to
+// completion.
+//
+// A maxInFlight of 0 or less means unbounded (all jobs start immediately).
+func Run(ctx context.Context, maxInFlight int, jobs []func(context.Context) error) error {
+	group, groupCtx := errgroup.WithContext(ctx)
+	if maxInFlight > 0 {
+		group.SetLimit(maxInFlight)
+	}
+	for _, job := range jobs {
+		job := job
+		group.Go(func() error {
+			return job(groupCtx)
+		})
+	}
+	return group.Wait()
+}
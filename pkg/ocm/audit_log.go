@@ -0,0 +1,36 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// AddAuditLogConfig sets the AWS.AuditLog.RoleArn field on a cluster
+// creation/update payload, when roleARN is non-empty, so the control plane
+// knows which role to assume to forward Kubernetes API-server audit logs to
+// AWS CloudWatch Logs. Used by 'rosa create service'.
+// TODO: 'rosa create cluster' should call this too, with its own
+// '--audit-log-arn' flag, and 'rosa edit cluster' should call it from the
+// update path so audit log forwarding can be toggled on an existing
+// cluster instead of only at creation time.
+func AddAuditLogConfig(builder *cmv1.ClusterBuilder, roleARN string) *cmv1.ClusterBuilder {
+	if roleARN == "" {
+		return builder
+	}
+	return builder.AWS(cmv1.NewAWS().AuditLog(cmv1.NewAuditLog().RoleArn(roleARN)))
+}
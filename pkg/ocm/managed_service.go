@@ -0,0 +1,50 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+// OperatorIAMRole is a single operator credential request's IAM role
+// binding: the namespace/name the operator authenticates as, and the role
+// ARN it assumes.
+type OperatorIAMRole struct {
+	Name      string
+	Namespace string
+	RoleARN   string
+}
+
+// CreateManagedServiceArgs collects the parameters needed to create a
+// managed service.
+type CreateManagedServiceArgs struct {
+	ServiceName            string
+	ClusterName            string
+	AwsRoleARN             string
+	AwsSupportRoleARN      string
+	AwsControlPlaneRoleARN string
+	AwsWorkerRoleARN       string
+	AwsOperatorIamRoleList []OperatorIAMRole
+	AwsAccountID           string
+	AwsRegion              string
+	Parameters             map[string]string
+
+	// AuditLogRoleARN is the ARN of the IAM role the control plane assumes
+	// to forward Kubernetes API-server audit logs to AWS CloudWatch Logs.
+	// Left empty, audit log forwarding stays disabled.
+	AuditLogRoleARN string
+
+	// EtcdEncryptionKmsARN is the ARN of the KMS key used to encrypt etcd
+	// data at rest. Left empty, OCM's default etcd encryption is used.
+	EtcdEncryptionKmsARN string
+}
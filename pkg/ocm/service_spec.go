@@ -0,0 +1,130 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	msv1 "github.com/openshift-online/ocm-sdk-go/servicemgmt/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// ServiceRoleARNs groups the account role ARNs a declarative service spec
+// can pin explicitly, instead of having rosa auto-discover them.
+type ServiceRoleARNs struct {
+	Installer    string `json:"installer,omitempty"`
+	Support      string `json:"support,omitempty"`
+	ControlPlane string `json:"controlPlane,omitempty"`
+	Worker       string `json:"worker,omitempty"`
+}
+
+// ManagedServiceSpec is the declarative, gitops-friendly description of a
+// managed service consumed by 'rosa create service --from-file' and emitted
+// by 'rosa describe service --output=spec'.
+type ManagedServiceSpec struct {
+	ServiceName          string            `json:"serviceName"`
+	ClusterName          string            `json:"clusterName"`
+	Region               string            `json:"region"`
+	Version              string            `json:"version"`
+	AwsRoleARNs          ServiceRoleARNs   `json:"awsRoleARNs"`
+	OperatorRolesPrefix  string            `json:"operatorRolesPrefix,omitempty"`
+	AuditLogRoleARN      string            `json:"auditLogRoleARN,omitempty"`
+	EtcdEncryptionKmsARN string            `json:"etcdEncryptionKmsARN,omitempty"`
+	Parameters           map[string]string `json:"parameters,omitempty"`
+}
+
+// ReadManagedServiceSpec loads and parses a declarative service spec from a
+// YAML or JSON file. YAML is accepted since it is converted to JSON before
+// unmarshalling.
+func ReadManagedServiceSpec(path string) (*ManagedServiceSpec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service spec '%s': %v", path, err)
+	}
+	spec := &ManagedServiceSpec{}
+	err = yaml.Unmarshal(data, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service spec '%s': %v", path, err)
+	}
+	if spec.ServiceName == "" {
+		return nil, fmt.Errorf("service spec '%s' is missing the required 'serviceName' field", path)
+	}
+	if spec.ClusterName == "" {
+		return nil, fmt.Errorf("service spec '%s' is missing the required 'clusterName' field", path)
+	}
+	return spec, nil
+}
+
+// ManagedServiceSpecFromService converts a managed service read back from
+// OCM into the declarative spec format, for 'rosa describe service
+// --output=spec'. The result round-trips through 'rosa create service
+// --from-file'.
+func ManagedServiceSpecFromService(service *msv1.ManagedService) *ManagedServiceSpec {
+	spec := &ManagedServiceSpec{
+		ServiceName: service.Service(),
+	}
+
+	cluster, ok := service.GetCluster()
+	if ok {
+		spec.ClusterName = cluster.Name()
+		if region, ok := cluster.GetRegion(); ok {
+			spec.Region = region.ID()
+		}
+		if version, ok := cluster.GetVersion(); ok {
+			spec.Version = GetVersionMinor(version.ID())
+		}
+		if aws, ok := cluster.GetAWS(); ok {
+			if sts, ok := aws.GetSTS(); ok {
+				spec.AwsRoleARNs = ServiceRoleARNs{
+					Installer:    sts.RoleARN(),
+					Support:      sts.SupportRoleARN(),
+					ControlPlane: sts.InstanceIAMRoles().MasterRoleARN(),
+					Worker:       sts.InstanceIAMRoles().WorkerRoleARN(),
+				}
+				spec.OperatorRolesPrefix = sts.OperatorRolePrefix()
+			}
+			if auditLog, ok := aws.GetAuditLog(); ok {
+				spec.AuditLogRoleARN = auditLog.RoleArn()
+			}
+			if kmsARN, ok := aws.GetKMSKeyArn(); ok {
+				spec.EtcdEncryptionKmsARN = kmsARN
+			}
+		}
+	}
+
+	if params, ok := service.GetParameters(); ok {
+		spec.Parameters = map[string]string{}
+		params.Each(func(param *cmv1.AddOnParameter) bool {
+			spec.Parameters[param.ID()] = param.Value()
+			return true
+		})
+	}
+
+	return spec
+}
+
+// MarshalManagedServiceSpec renders a spec as YAML, for 'rosa describe
+// service --output=spec'.
+func MarshalManagedServiceSpec(spec *ManagedServiceSpec) (string, error) {
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to render service spec: %v", err)
+	}
+	return string(data), nil
+}
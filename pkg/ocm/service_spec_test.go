@@ -0,0 +1,93 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestManagedServiceSpecRoundTrip(t *testing.T) {
+	original := &ManagedServiceSpec{
+		ServiceName: "service1",
+		ClusterName: "my-cluster",
+		Region:      "us-east-1",
+		Version:     "4.11",
+		AwsRoleARNs: ServiceRoleARNs{
+			Installer:    "arn:aws:iam::123456789012:role/my-cluster-Installer-Role",
+			Support:      "arn:aws:iam::123456789012:role/my-cluster-Support-Role",
+			ControlPlane: "arn:aws:iam::123456789012:role/my-cluster-ControlPlane-Role",
+			Worker:       "arn:aws:iam::123456789012:role/my-cluster-Worker-Role",
+		},
+		OperatorRolesPrefix: "my-cluster-abcd",
+		AuditLogRoleARN:     "arn:aws:iam::123456789012:role/my-cluster-Audit-Log",
+		Parameters:          map[string]string{"foo": "bar"},
+	}
+
+	rendered, err := MarshalManagedServiceSpec(original)
+	if err != nil {
+		t.Fatalf("MarshalManagedServiceSpec returned an error: %v", err)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec.yaml")
+	if err := ioutil.WriteFile(path, []byte(rendered), 0600); err != nil {
+		t.Fatalf("failed to write spec file: %v", err)
+	}
+
+	parsed, err := ReadManagedServiceSpec(path)
+	if err != nil {
+		t.Fatalf("ReadManagedServiceSpec returned an error: %v", err)
+	}
+
+	if !reflect.DeepEqual(parsed, original) {
+		t.Fatalf("round-tripped spec %+v does not match original %+v", parsed, original)
+	}
+}
+
+func TestReadManagedServiceSpecRequiresServiceName(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec.yaml")
+	if err := ioutil.WriteFile(path, []byte("clusterName: my-cluster\n"), 0600); err != nil {
+		t.Fatalf("failed to write spec file: %v", err)
+	}
+
+	if _, err := ReadManagedServiceSpec(path); err == nil {
+		t.Fatal("expected an error for a spec missing 'serviceName', got nil")
+	}
+}
+
+func TestReadManagedServiceSpecRequiresClusterName(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec.yaml")
+	if err := ioutil.WriteFile(path, []byte("serviceName: service1\n"), 0600); err != nil {
+		t.Fatalf("failed to write spec file: %v", err)
+	}
+
+	if _, err := ReadManagedServiceSpec(path); err == nil {
+		t.Fatal("expected an error for a spec missing 'clusterName', got nil")
+	}
+}
+
+func TestReadManagedServiceSpecMissingFile(t *testing.T) {
+	if _, err := ReadManagedServiceSpec(filepath.Join(os.TempDir(), "does-not-exist.yaml")); err == nil {
+		t.Fatal("expected an error for a missing file, got nil")
+	}
+}